@@ -4,8 +4,6 @@
 //
 // TODO: Adding to the pool. (Add the given vdevs to the pool)
 // TODO: Scan for pools.
-//
-//
 package zfs
 
 /*
@@ -98,6 +96,22 @@ const (
 
 	// Finally, the following indicates a healthy pool.
 	PoolStatusOk
+
+	// The following were added by newer OpenZFS releases after the set above
+	// was mirrored from illumos. They are appended here, rather than
+	// inserted in the position the upstream C enum uses, so that the
+	// ordinals of the existing constants above never shift. Because of
+	// this, these ordinals do NOT match the corresponding ZPOOL_STATUS_*
+	// values in zpool_status_t; poolStatusFromC() in pool_status.go maps
+	// between the two explicitly rather than relying on a raw cast.
+	PoolStatusHostidActive     // pool is imported and actively in use on another system
+	PoolStatusHostidRequired   // multihost=on is required, but not set
+	PoolStatusIoFailureMmp     // failed MMP, failmode not 'continue'
+	PoolStatusNonNativeAshift  // one or more devices are configured to use a non-native block size
+	PoolStatusRebuilding       // one or more top-level vdevs are being sequentially resilvered
+	PoolStatusRebuildScrub     // a scrub is required after a sequential resilver completes
+	PoolStatusCompatibilityErr // bad value for 'compatibility' property
+	PoolStatusIncompatibleFeat // feature set outside of compatibility feature sets
 )
 
 func (s PoolStatus) String() string {
@@ -157,6 +171,23 @@ func (s PoolStatus) String() string {
 	case PoolStatusOk:
 		return "healthy"
 
+	case PoolStatusHostidActive:
+		return "pool is imported and in use on another system"
+	case PoolStatusHostidRequired:
+		return "multihost protection is required, but not enabled"
+	case PoolStatusIoFailureMmp:
+		return "failed MMP write; failmode is not 'continue'"
+	case PoolStatusNonNativeAshift:
+		return "one or more devices are configured to use a non-native block size"
+	case PoolStatusRebuilding:
+		return "one or more devices is currently being resilvered"
+	case PoolStatusRebuildScrub:
+		return "a scrub is required after the sequential resilver completes"
+	case PoolStatusCompatibilityErr:
+		return "error reading or parsing the compatibility property"
+	case PoolStatusIncompatibleFeat:
+		return "one or more features are enabled that are not in the compatibility feature set"
+
 	default:
 		return "UNKNOWN"
 	}
@@ -302,6 +333,14 @@ const (
 	PoolNumProps
 )
 
+// PoolPropAutotrim is declared outside of the iota block above rather than
+// appended after PoolNumProps: GetProperty/SetProperty cast a Prop straight
+// to the C zpool_prop_t, so an arbitrary Go-side ordinal would get or set
+// the wrong property. Since "autotrim" was added to zpool_prop_t after the
+// properties mirrored above, its only stable representation here is the
+// actual C enumerator value.
+var PoolPropAutotrim = Prop(C.ZPOOL_PROP_AUTOTRIM)
+
 /*
  * Dataset properties are identified by these constants and must be added to
  * the end of this list to ensure that external consumers are not affected
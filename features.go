@@ -0,0 +1,199 @@
+package zfs
+
+/*
+#cgo CFLAGS: -I /usr/include/libzfs -I /usr/include/libspl -DHAVE_IOCTL_IN_SYS_IOCTL_H
+#cgo LDFLAGS: -lzfs -lzpool -lnvpair
+
+#include <stdlib.h>
+#include <libzfs.h>
+#include "zpool.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// cZpoolConfigUnsupFeat is hoisted once, rather than C.CString()'d on every
+// call, since UnsupportedFeatures is exactly the call a poller/exporter
+// runs in a loop.
+var cZpoolConfigUnsupFeat = C.CString(C.ZPOOL_CONFIG_UNSUP_FEAT)
+
+// FeatureState describes the per-pool state of a feature flag, as reported
+// via the "feature@<name>" property namespace.
+type FeatureState int
+
+// Possible values for FeatureState.
+const (
+	FeatureStateDisabled FeatureState = iota
+	FeatureStateEnabled
+	FeatureStateActive
+)
+
+func (s FeatureState) String() string {
+	switch s {
+	case FeatureStateDisabled:
+		return "disabled"
+	case FeatureStateEnabled:
+		return "enabled"
+	case FeatureStateActive:
+		return "active"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Feature describes a single OpenZFS pool feature, as documented in
+// zpool-features(7) and enumerated by spa_feature_table in libzfs.
+type Feature struct {
+	// GUID is the on-disk, globally unique feature name (e.g.
+	// "org.open-zfs:large_blocks").
+	GUID string
+
+	// Name is the short name used in the "feature@<name>" property (e.g.
+	// "large_blocks").
+	Name string
+
+	// ReadOnlyCompat is true if a pool using this feature can still be
+	// imported read-only by software that doesn't understand it.
+	ReadOnlyCompat bool
+
+	// Description is the one-line human-readable description of the
+	// feature, as printed by 'zpool upgrade -v'.
+	Description string
+
+	// DependsOn lists the GUIDs of features this feature depends on.
+	DependsOn []string
+}
+
+// featureTable mirrors spa_feature_table (module/zcommon/zfeature_common.c).
+// It is hardcoded here rather than scraped from libzfs so that ListFeatures
+// does not require an open pool handle.
+var featureTable = []Feature{
+	{GUID: "com.delphix:async_destroy", Name: "async_destroy", ReadOnlyCompat: true,
+		Description: "Destroy filesystems asynchronously."},
+	{GUID: "com.delphix:empty_bpobj", Name: "empty_bpobj", ReadOnlyCompat: true,
+		Description: "Snapshots use less space."},
+	{GUID: "org.illumos:lz4_compress", Name: "lz4_compress",
+		Description: "LZ4 compression algorithm support."},
+	{GUID: "com.delphix:spacemap_histogram", Name: "spacemap_histogram", ReadOnlyCompat: true,
+		Description: "Spacemaps maintain space histograms."},
+	{GUID: "com.delphix:extensible_dataset", Name: "extensible_dataset",
+		Description: "Enhanced dataset functionality, used by other features."},
+	{GUID: "com.delphix:bookmarks", Name: "bookmarks", ReadOnlyCompat: true,
+		Description: "\"zfs bookmark\" command.", DependsOn: []string{"com.delphix:extensible_dataset"}},
+	{GUID: "com.joyent:filesystem_limits", Name: "filesystem_limits", ReadOnlyCompat: true,
+		Description: "Filesystem and snapshot limits."},
+	{GUID: "com.delphix:embedded_data", Name: "embedded_data",
+		Description: "Blocks which compress very well use even less space."},
+	{GUID: "org.zfsonlinux:large_dnode", Name: "large_dnode",
+		Description: "Variable on-disk size of dnodes."},
+	{GUID: "com.delphix:spacemap_v2", Name: "spacemap_v2", ReadOnlyCompat: true,
+		Description: "Space maps representation is optimized for compression."},
+	{GUID: "org.open-zfs:large_blocks", Name: "large_blocks",
+		Description: "Support for blocks larger than 128KB.", DependsOn: []string{"com.delphix:extensible_dataset"}},
+	{GUID: "org.illumos:sha512", Name: "sha512",
+		Description: "SHA-512/256 hash algorithm."},
+	{GUID: "org.illumos:skein", Name: "skein",
+		Description: "Skein hash algorithm."},
+	{GUID: "org.illumos:edonr", Name: "edonr",
+		Description: "Edon-R hash algorithm."},
+	{GUID: "org.zfsonlinux:userobj_accounting", Name: "userobj_accounting", ReadOnlyCompat: true,
+		Description: "User/group dataset object accounting."},
+	{GUID: "com.datto:encryption", Name: "encryption",
+		Description: "Support for dataset level encryption.", DependsOn: []string{"com.delphix:bookmarks", "com.delphix:extensible_dataset"}},
+	{GUID: "com.datto:resilver_defer", Name: "resilver_defer", ReadOnlyCompat: true,
+		Description: "Support for deferring new resilvers when one is already running."},
+	{GUID: "com.delphix:device_removal", Name: "device_removal",
+		Description: "Top-level vdevs can be removed, reducing logical pool size."},
+	{GUID: "org.zfsonlinux:allocation_classes", Name: "allocation_classes", ReadOnlyCompat: true,
+		Description: "Support for separate allocation classes."},
+	{GUID: "org.freebsd:zstd_compress", Name: "zstd_compress",
+		Description: "zstd compression algorithm support."},
+}
+
+// ListFeatures returns the set of OpenZFS pool features known to this
+// package, as documented in zpool-features(7).
+func ListFeatures() []Feature {
+	out := make([]Feature, len(featureTable))
+	copy(out, featureTable)
+	return out
+}
+
+func parseFeatureState(s string) (FeatureState, error) {
+	switch s {
+	case "disabled":
+		return FeatureStateDisabled, nil
+	case "enabled":
+		return FeatureStateEnabled, nil
+	case "active":
+		return FeatureStateActive, nil
+	default:
+		return FeatureStateDisabled, fmt.Errorf("unrecognized feature state %q", s)
+	}
+}
+
+// FeatureState returns the current per-pool state of the named feature (by
+// its short "feature@<name>" name, e.g. "large_blocks").
+func (p *Pool) FeatureState(name string) (FeatureState, error) {
+	propName := C.CString("feature@" + name)
+	defer C.free(unsafe.Pointer(propName))
+
+	buf := make([]C.char, 32)
+	if rc := C.zpool_prop_get_feature(p.poolHandle, propName, &buf[0], C.size_t(len(buf))); rc != 0 {
+		return FeatureStateDisabled, LastError()
+	}
+	return parseFeatureState(C.GoString(&buf[0]))
+}
+
+// EnableFeature enables a single feature (by its short "feature@<name>"
+// name) on the pool, equivalent to 'zpool set feature@<name>=enabled'.
+func (p *Pool) EnableFeature(name string) error {
+	propName := C.CString("feature@" + name)
+	defer C.free(unsafe.Pointer(propName))
+	propVal := C.CString("enabled")
+	defer C.free(unsafe.Pointer(propVal))
+
+	if rc := C.zpool_set_prop(p.poolHandle, propName, propVal); rc != 0 {
+		return LastError()
+	}
+	return nil
+}
+
+// EnableAllFeatures enables every feature known to this package on the
+// pool, equivalent to 'zpool upgrade'.
+func (p *Pool) EnableAllFeatures() error {
+	for _, f := range featureTable {
+		if err := p.EnableFeature(f.Name); err != nil {
+			return fmt.Errorf("enabling feature %q: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// UnsupportedFeatures returns the names of the features that are blocking
+// this pool from being imported (PoolStatusUnsupFeatRead) or imported
+// read-write (PoolStatusUnsupFeatWrite), read out of the pool config's
+// ZPOOL_CONFIG_UNSUP_FEAT nvlist.
+func (p *Pool) UnsupportedFeatures() ([]string, error) {
+	config := C.zpool_get_config(p.poolHandle, nil)
+	if config == nil {
+		return nil, LastError()
+	}
+
+	var unsupFeat *C.nvlist_t
+	if C.nvlist_lookup_nvlist(config, cZpoolConfigUnsupFeat, &unsupFeat) != 0 {
+		// No unsupported features nvlist; nothing is blocking the pool.
+		return nil, nil
+	}
+
+	var names []string
+	pair := C.nvlist_next_nvpair(unsupFeat, nil)
+	for pair != nil {
+		names = append(names, strings.TrimSpace(C.GoString(C.nvpair_name(pair))))
+		pair = C.nvlist_next_nvpair(unsupFeat, pair)
+	}
+	return names, nil
+}
@@ -0,0 +1,52 @@
+package zfs
+
+import "testing"
+
+func TestParseFeatureState(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    FeatureState
+		wantErr bool
+	}{
+		{"disabled", FeatureStateDisabled, false},
+		{"enabled", FeatureStateEnabled, false},
+		{"active", FeatureStateActive, false},
+		{"bogus", FeatureStateDisabled, true},
+		{"", FeatureStateDisabled, true},
+	}
+
+	for _, c := range cases {
+		got, err := parseFeatureState(c.in)
+		if (err != nil) != c.wantErr {
+			t.Errorf("parseFeatureState(%q) error = %v, wantErr %v", c.in, err, c.wantErr)
+			continue
+		}
+		if err == nil && got != c.want {
+			t.Errorf("parseFeatureState(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestFeatureTableEntriesWellFormed(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, f := range featureTable {
+		if f.GUID == "" || f.Name == "" {
+			t.Errorf("feature %+v has an empty GUID or Name", f)
+		}
+		if seen[f.GUID] {
+			t.Errorf("duplicate feature GUID %q in featureTable", f.GUID)
+		}
+		seen[f.GUID] = true
+	}
+}
+
+func TestListFeaturesReturnsACopy(t *testing.T) {
+	got := ListFeatures()
+	if len(got) == 0 {
+		t.Fatal("ListFeatures() returned no features")
+	}
+	got[0].Name = "mutated"
+	if featureTable[0].Name == "mutated" {
+		t.Error("ListFeatures() exposed the internal featureTable slice to caller mutation")
+	}
+}
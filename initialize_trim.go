@@ -0,0 +1,282 @@
+package zfs
+
+/*
+#cgo CFLAGS: -I /usr/include/libzfs -I /usr/include/libspl -DHAVE_IOCTL_IN_SYS_IOCTL_H
+#cgo LDFLAGS: -lzfs -lzpool -lnvpair
+
+#include <stdlib.h>
+#include <libzfs.h>
+#include "zpool.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+var (
+	errNvlistAlloc = errors.New("zfs: failed to allocate nvlist")
+	errNoVdevStats = errors.New("zfs: vdev config has no vdev_stats")
+)
+
+// cZpoolConfigVdevStats is hoisted once, rather than C.CString()'d on every
+// call, since InitializeStats/TrimStats are exactly the calls a
+// poller/exporter runs in a loop.
+var cZpoolConfigVdevStats = C.CString(C.ZPOOL_CONFIG_VDEV_STATS)
+
+// InitializeCmd selects the action Pool.Initialize() takes on the given
+// vdevs. Corresponds to pool_initialize_func_t in include/sys/fs/zfs.h.
+type InitializeCmd uint64
+
+// Possible values for InitializeCmd.
+const (
+	InitializeCmdStart InitializeCmd = iota
+	InitializeCmdCancel
+	InitializeCmdSuspend
+)
+
+func (c InitializeCmd) String() string {
+	switch c {
+	case InitializeCmdStart:
+		return "start"
+	case InitializeCmdCancel:
+		return "cancel"
+	case InitializeCmdSuspend:
+		return "suspend"
+	default:
+		return "<UNKNOWN-VALUE>"
+	}
+}
+
+// TrimCmd selects the action Pool.Trim() takes on the given vdevs.
+// Corresponds to pool_trim_func_t in include/sys/fs/zfs.h.
+type TrimCmd uint64
+
+// Possible values for TrimCmd.
+const (
+	TrimCmdStart TrimCmd = iota
+	TrimCmdCancel
+	TrimCmdSuspend
+)
+
+func (c TrimCmd) String() string {
+	switch c {
+	case TrimCmdStart:
+		return "start"
+	case TrimCmdCancel:
+		return "cancel"
+	case TrimCmdSuspend:
+		return "suspend"
+	default:
+		return "<UNKNOWN-VALUE>"
+	}
+}
+
+// VDevInitializeState describes a vdev's vs_initialize_state, as set by
+// Pool.Initialize(). Corresponds to vdev_initializing_state_t in
+// include/sys/fs/zfs.h; it is a distinct type from DSLScanState because the
+// two C enums do not share a common ordinal layout.
+type VDevInitializeState uint64
+
+// Possible values for VDevInitializeState.
+const (
+	VDevInitializeNone VDevInitializeState = iota
+	VDevInitializeActive
+	VDevInitializeCanceled
+	VDevInitializeSuspended
+	VDevInitializeComplete
+)
+
+func (s VDevInitializeState) String() string {
+	switch s {
+	case VDevInitializeNone:
+		return "none"
+	case VDevInitializeActive:
+		return "active"
+	case VDevInitializeCanceled:
+		return "canceled"
+	case VDevInitializeSuspended:
+		return "suspended"
+	case VDevInitializeComplete:
+		return "complete"
+	default:
+		return "<UNKNOWN-VALUE>"
+	}
+}
+
+// VDevTrimState describes a vdev's vs_trim_state, as set by Pool.Trim().
+// Corresponds to vdev_trim_state_t in include/sys/fs/zfs.h; it is a distinct
+// type from DSLScanState because the two C enums do not share a common
+// ordinal layout.
+type VDevTrimState uint64
+
+// Possible values for VDevTrimState.
+const (
+	VDevTrimNone VDevTrimState = iota
+	VDevTrimActive
+	VDevTrimCanceled
+	VDevTrimSuspended
+	VDevTrimComplete
+)
+
+func (s VDevTrimState) String() string {
+	switch s {
+	case VDevTrimNone:
+		return "none"
+	case VDevTrimActive:
+		return "active"
+	case VDevTrimCanceled:
+		return "canceled"
+	case VDevTrimSuspended:
+		return "suspended"
+	case VDevTrimComplete:
+		return "complete"
+	default:
+		return "<UNKNOWN-VALUE>"
+	}
+}
+
+// TrimOptions controls a Pool.Trim() call.
+type TrimOptions struct {
+	// Rate caps the TRIM rate in bytes/sec, per vdev. Zero means
+	// unlimited.
+	Rate uint64
+
+	// Secure requests a secure TRIM, which also erases unmapped data so it
+	// cannot be recovered, on devices that support it.
+	Secure bool
+
+	Cmd TrimCmd
+}
+
+// vdevNameList builds the nvlist_t of vdev path/guid -> boolean that
+// zpool_initialize/zpool_trim take to select which vdevs to operate on.
+func vdevNameList(vdevs []string) (*C.nvlist_t, error) {
+	var nvl *C.nvlist_t
+	if rc := C.nvlist_alloc(&nvl, C.NV_UNIQUE_NAME, 0); rc != 0 {
+		return nil, errNvlistAlloc
+	}
+
+	for _, name := range vdevs {
+		cName := C.CString(name)
+		C.fnvlist_add_boolean(nvl, cName)
+		C.free(unsafe.Pointer(cName))
+	}
+
+	return nvl, nil
+}
+
+// Initialize starts, suspends, or cancels initialization (zero-filling
+// never-allocated regions) of the given vdevs, wrapping zpool_initialize.
+func (p *Pool) Initialize(vdevs []string, cmd InitializeCmd) error {
+	nvl, err := vdevNameList(vdevs)
+	if err != nil {
+		return err
+	}
+	defer C.nvlist_free(nvl)
+
+	if rc := C.zpool_initialize(p.poolHandle, C.pool_initialize_func_t(cmd), nvl); rc != 0 {
+		return LastError()
+	}
+	return nil
+}
+
+// Trim starts, suspends, or cancels a TRIM of the given vdevs, wrapping
+// zpool_trim.
+func (p *Pool) Trim(vdevs []string, opts TrimOptions) error {
+	nvl, err := vdevNameList(vdevs)
+	if err != nil {
+		return err
+	}
+	defer C.nvlist_free(nvl)
+
+	var flags C.trimflags_t
+	flags.rate = C.uint64_t(opts.Rate)
+	flags.secure = booleanT(opts.Secure)
+
+	if rc := C.zpool_trim(p.poolHandle, C.pool_trim_func_t(opts.Cmd), nvl, &flags); rc != 0 {
+		return LastError()
+	}
+	return nil
+}
+
+// InitializeStats mirrors the vs_initialize_* members of vdev_stat_t
+// (include/sys/fs/zfs.h).
+type InitializeStats struct {
+	State      VDevInitializeState
+	ActionTime time.Time
+	BytesDone  uint64
+	BytesEst   uint64
+}
+
+// TrimStats mirrors the vs_trim_* members of vdev_stat_t.
+type TrimStats struct {
+	State      VDevTrimState
+	ActionTime time.Time
+	BytesDone  uint64
+	BytesEst   uint64
+}
+
+// vdevStat looks up and returns this vdev's ZPOOL_CONFIG_VDEV_STATS blob
+// (a packed vdev_stat_t), as found in its config nvlist.
+func (v *VDev) vdevStat() (*C.vdev_stat_t, error) {
+	var stats *C.uint64_t
+	var nelem C.uint_t
+	if C.nvlist_lookup_uint64_array(v.Nvlist(), cZpoolConfigVdevStats, &stats, &nelem) != 0 {
+		return nil, errNoVdevStats
+	}
+	return (*C.vdev_stat_t)(unsafe.Pointer(stats)), nil
+}
+
+// InitializeStats reads this vdev's vs_initialize_* stats, reflecting
+// progress of any Pool.Initialize() call made against it.
+func (v *VDev) InitializeStats() (InitializeStats, error) {
+	vs, err := v.vdevStat()
+	if err != nil {
+		return InitializeStats{}, err
+	}
+
+	return InitializeStats{
+		State:      VDevInitializeState(vs.vs_initialize_state),
+		ActionTime: time.Unix(int64(vs.vs_initialize_action_time), 0),
+		BytesDone:  uint64(vs.vs_initialize_bytes_done),
+		BytesEst:   uint64(vs.vs_initialize_bytes_est),
+	}, nil
+}
+
+// TrimStats reads this vdev's vs_trim_* stats, reflecting progress of any
+// Pool.Trim() call made against it.
+func (v *VDev) TrimStats() (TrimStats, error) {
+	vs, err := v.vdevStat()
+	if err != nil {
+		return TrimStats{}, err
+	}
+
+	return TrimStats{
+		State:      VDevTrimState(vs.vs_trim_state),
+		ActionTime: time.Unix(int64(vs.vs_trim_action_time), 0),
+		BytesDone:  uint64(vs.vs_trim_bytes_done),
+		BytesEst:   uint64(vs.vs_trim_bytes_est),
+	}, nil
+}
+
+// AutotrimEnabled reports whether the pool's "autotrim" property is set.
+func (p *Pool) AutotrimEnabled() (bool, error) {
+	prop, err := p.GetProperty(PoolPropAutotrim)
+	if err != nil {
+		return false, err
+	}
+	return prop.Value == "on", nil
+}
+
+// SetAutotrim enables or disables the pool's "autotrim" property, which
+// causes the pool to automatically TRIM freed space in the background.
+func (p *Pool) SetAutotrim(enabled bool) error {
+	val := "off"
+	if enabled {
+		val = "on"
+	}
+	return p.SetProperty(PoolPropAutotrim, val)
+}
@@ -0,0 +1,310 @@
+package zfs
+
+/*
+#cgo CFLAGS: -I /usr/include/libzfs -I /usr/include/libspl -DHAVE_IOCTL_IN_SYS_IOCTL_H
+#cgo LDFLAGS: -lzfs -lzpool -lnvpair
+
+#include <stdlib.h>
+#include <libzfs.h>
+#include "zpool.h"
+
+// Newer OpenZFS releases added zpool_status_t values that don't exist in
+// older libzfs headers. Guard each one so this package still builds against
+// an older libzfs, falling back to a value this file never otherwise
+// produces so poolStatusFromC's switch below can tell them apart.
+#ifndef ZPOOL_STATUS_HOSTID_ACTIVE
+#define ZPOOL_STATUS_HOSTID_ACTIVE ((zpool_status_t)-1)
+#endif
+#ifndef ZPOOL_STATUS_HOSTID_REQUIRED
+#define ZPOOL_STATUS_HOSTID_REQUIRED ((zpool_status_t)-2)
+#endif
+#ifndef ZPOOL_STATUS_IO_FAILURE_MMP
+#define ZPOOL_STATUS_IO_FAILURE_MMP ((zpool_status_t)-3)
+#endif
+#ifndef ZPOOL_STATUS_NON_NATIVE_ASHIFT
+#define ZPOOL_STATUS_NON_NATIVE_ASHIFT ((zpool_status_t)-4)
+#endif
+#ifndef ZPOOL_STATUS_REBUILDING
+#define ZPOOL_STATUS_REBUILDING ((zpool_status_t)-5)
+#endif
+#ifndef ZPOOL_STATUS_REBUILD_SCRUB
+#define ZPOOL_STATUS_REBUILD_SCRUB ((zpool_status_t)-6)
+#endif
+#ifndef ZPOOL_STATUS_COMPATIBILITY_ERR
+#define ZPOOL_STATUS_COMPATIBILITY_ERR ((zpool_status_t)-7)
+#endif
+#ifndef ZPOOL_STATUS_INCOMPATIBLE_FEAT
+#define ZPOOL_STATUS_INCOMPATIBLE_FEAT ((zpool_status_t)-8)
+#endif
+*/
+import "C"
+
+// PoolErrata represents an informational errata that may be associated with
+// a pool whose PoolStatus is PoolStatusErrata. Corresponds to zpool_errata_t
+// in libzfs.h.
+type PoolErrata int
+
+// Possible values for PoolErrata.
+const (
+	PoolErrataNone PoolErrata = iota
+	PoolErrataZolExtentOpen
+	PoolErrataZolTinyAsize
+	PoolErrataZolEncryptionOnDraid
+	PoolErrataZolEncryptionAttributes
+)
+
+func (e PoolErrata) String() string {
+	switch e {
+	case PoolErrataNone:
+		return "none"
+	case PoolErrataZolExtentOpen:
+		return "scrubbing the pool is recommended"
+	case PoolErrataZolTinyAsize:
+		return "the on-disk size of some blocks may be incorrect"
+	case PoolErrataZolEncryptionOnDraid:
+		return "encrypted datasets on a draid vdev may be inaccessible"
+	case PoolErrataZolEncryptionAttributes:
+		return "some encrypted datasets have incorrect extensible attributes"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// PoolStatusReport is the Go-side equivalent of what libzfs_status.c computes
+// for a pool: the status code itself, plus the human-readable remediation
+// text and stable reference URL that the zpool(8) CLI prints alongside it.
+type PoolStatusReport struct {
+	Status PoolStatus
+
+	// Action is the suggested administrative remediation, e.g. "Attach the
+	// missing device and online it using 'zpool online'." Empty if the
+	// status requires no action. Also empty for every status appended
+	// after PoolStatusOk (PoolStatusHostidActive through
+	// PoolStatusIncompatibleFeat): poolStatusTable has no entry for them.
+	Action string
+
+	// Reference is the ZFS message-id URL for this status
+	// (e.g. "http://zfsonlinux.org/msg/ZFS-8000-2Q"), or empty if the
+	// status has no corresponding message ID or no entry in
+	// poolStatusTable.
+	Reference string
+
+	// Errata is populated when Status is PoolStatusErrata.
+	Errata PoolErrata
+}
+
+// poolStatusInfo is the Go-side mirror of the per-status (msgid, action)
+// table in libzfs_status.c's zpool_explain_status(). Keyed off PoolStatus so
+// callers building monitoring UIs don't have to re-implement it.
+type poolStatusInfo struct {
+	action    string
+	reference string
+}
+
+var poolStatusTable = map[PoolStatus]poolStatusInfo{
+	PoolStatusCorruptCache: {
+		action:    "Restart the pool and import the pool",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-14",
+	},
+	PoolStatusMissingDevR: {
+		action:    "Attach the missing device and online it using 'zpool online'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-2Q",
+	},
+	PoolStatusMissingDevNr: {
+		action:    "Attach the missing device and online it using 'zpool online'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-3C",
+	},
+	PoolStatusCorruptLabelR: {
+		action:    "Replace the faulted device, or use 'zpool clear' to mark the device repaired.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-4J",
+	},
+	PoolStatusCorruptLabelNr: {
+		action:    "Destroy and re-create the pool from a backup source.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-5E",
+	},
+	PoolStatusBadGUIDSum: {
+		action:    "Attach the missing device and online it using 'zpool online'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-3C",
+	},
+	PoolStatusCorruptPool: {
+		action:    "Destroy and re-create the pool from a backup source.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-72",
+	},
+	PoolStatusCorruptData: {
+		action:    "Restore the file in question if possible. Otherwise restore the entire pool from backup.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-8A",
+	},
+	PoolStatusFailingDev: {
+		action:    "Determine if the device needs to be replaced, and clear the errors using 'zpool clear' or replace the device with 'zpool replace'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-9P",
+	},
+	PoolStatusVersionNewer: {
+		action:    "Access the pool from a system running more recent software, or restore the pool from backup.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-A5",
+	},
+	PoolStatusHostidMismatch: {
+		action:    "Import the pool using 'zpool import -f', or ensure that the pool is not already active on another system.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-EY",
+	},
+	PoolStatusIoFailureWait: {
+		action:    "Make sure the affected devices are connected, then run 'zpool clear'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-HC",
+	},
+	PoolStatusIoFailureContinue: {
+		action:    "Make sure the affected devices are connected, then run 'zpool clear'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-JQ",
+	},
+	PoolStatusBadLog: {
+		action:    "The log device is not required for correct operation unless the 'failmode' property is set to 'continue' or 'panic'. Replace the device and run 'zpool clear'.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-K4",
+	},
+	PoolStatusErrata: {
+		action:    "Errata information is available in the release notes corresponding to this version.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-ER",
+	},
+	// UnsupFeatRead and UnsupFeatWrite share a reference: both land on the
+	// same "unsupported feature(s)" msgid in zfs_msgid_table, differing
+	// only in the recommended action.
+	PoolStatusUnsupFeatRead: {
+		action:    "Access the pool from a system that supports the required feature(s), or restore the pool from backup.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-6X",
+	},
+	PoolStatusUnsupFeatWrite: {
+		action:    "Enable all features using 'zpool upgrade', or only import the pool read-only.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-6X",
+	},
+	PoolStatusFaultedDevR: {
+		action:    "Replace the faulted device, or use 'zpool clear' to mark the device repaired.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-9P",
+	},
+	PoolStatusFaultedDevNr: {
+		action:    "Destroy and re-create the pool from a backup source.",
+		reference: "http://zfsonlinux.org/msg/ZFS-8000-9P",
+	},
+	PoolStatusVersionOlder: {
+		action: "Upgrade the pool using 'zpool upgrade'. Once this is done, the pool will no longer be accessible on software that does not support the feature flags format.",
+	},
+	PoolStatusFeatDisabled: {
+		action: "Enable all features using 'zpool upgrade'. Once this is done, the pool may no longer be accessible by software that does not support the features.",
+	},
+	PoolStatusResilvering: {
+		action: "Wait for the resilver to complete.",
+	},
+	PoolStatusOfflineDev: {
+		action: "Online the device using 'zpool online' or replace the device with 'zpool replace'.",
+	},
+	PoolStatusRemovedDev: {
+		action: "Online the device using 'zpool online' or replace the device with 'zpool replace'.",
+	},
+}
+
+// poolStatusFromC translates a zpool_status_t into our PoolStatus via an
+// exhaustive, explicit mapping rather than a raw ordinal cast. The two
+// enums' ordinals do NOT line up: the Go PoolStatus block mirrors an old
+// illumos zpool_status_t, modern libzfs has since inserted
+// ZPOOL_STATUS_HOSTID_ACTIVE/_REQUIRED and ZPOOL_STATUS_IO_FAILURE_MMP in
+// the middle of the C enum, and the newer status codes are appended to the
+// end of the Go block (rather than at the C enum's position) to avoid
+// shifting the existing Go ordinals. A direct cast would silently produce
+// the wrong status for every C value from ZPOOL_STATUS_HOSTID_ACTIVE
+// onward.
+func poolStatusFromC(s C.zpool_status_t) PoolStatus {
+	switch s {
+	case C.ZPOOL_STATUS_CORRUPT_CACHE:
+		return PoolStatusCorruptCache
+	case C.ZPOOL_STATUS_MISSING_DEV_R:
+		return PoolStatusMissingDevR
+	case C.ZPOOL_STATUS_MISSING_DEV_NR:
+		return PoolStatusMissingDevNr
+	case C.ZPOOL_STATUS_CORRUPT_LABEL_R:
+		return PoolStatusCorruptLabelR
+	case C.ZPOOL_STATUS_CORRUPT_LABEL_NR:
+		return PoolStatusCorruptLabelNr
+	case C.ZPOOL_STATUS_BAD_GUID_SUM:
+		return PoolStatusBadGUIDSum
+	case C.ZPOOL_STATUS_CORRUPT_POOL:
+		return PoolStatusCorruptPool
+	case C.ZPOOL_STATUS_CORRUPT_DATA:
+		return PoolStatusCorruptData
+	case C.ZPOOL_STATUS_FAILING_DEV:
+		return PoolStatusFailingDev
+	case C.ZPOOL_STATUS_VERSION_NEWER:
+		return PoolStatusVersionNewer
+	case C.ZPOOL_STATUS_HOSTID_MISMATCH:
+		return PoolStatusHostidMismatch
+	case C.ZPOOL_STATUS_HOSTID_ACTIVE:
+		return PoolStatusHostidActive
+	case C.ZPOOL_STATUS_HOSTID_REQUIRED:
+		return PoolStatusHostidRequired
+	case C.ZPOOL_STATUS_IO_FAILURE_WAIT:
+		return PoolStatusIoFailureWait
+	case C.ZPOOL_STATUS_IO_FAILURE_CONTINUE:
+		return PoolStatusIoFailureContinue
+	case C.ZPOOL_STATUS_IO_FAILURE_MMP:
+		return PoolStatusIoFailureMmp
+	case C.ZPOOL_STATUS_BAD_LOG:
+		return PoolStatusBadLog
+	case C.ZPOOL_STATUS_ERRATA:
+		return PoolStatusErrata
+
+	case C.ZPOOL_STATUS_UNSUP_FEAT_READ:
+		return PoolStatusUnsupFeatRead
+	case C.ZPOOL_STATUS_UNSUP_FEAT_WRITE:
+		return PoolStatusUnsupFeatWrite
+
+	case C.ZPOOL_STATUS_FAULTED_DEV_R:
+		return PoolStatusFaultedDevR
+	case C.ZPOOL_STATUS_FAULTED_DEV_NR:
+		return PoolStatusFaultedDevNr
+
+	case C.ZPOOL_STATUS_VERSION_OLDER:
+		return PoolStatusVersionOlder
+	case C.ZPOOL_STATUS_FEAT_DISABLED:
+		return PoolStatusFeatDisabled
+	case C.ZPOOL_STATUS_RESILVERING:
+		return PoolStatusResilvering
+	case C.ZPOOL_STATUS_OFFLINE_DEV:
+		return PoolStatusOfflineDev
+	case C.ZPOOL_STATUS_REMOVED_DEV:
+		return PoolStatusRemovedDev
+
+	case C.ZPOOL_STATUS_NON_NATIVE_ASHIFT:
+		return PoolStatusNonNativeAshift
+	case C.ZPOOL_STATUS_REBUILDING:
+		return PoolStatusRebuilding
+	case C.ZPOOL_STATUS_REBUILD_SCRUB:
+		return PoolStatusRebuildScrub
+	case C.ZPOOL_STATUS_COMPATIBILITY_ERR:
+		return PoolStatusCompatibilityErr
+	case C.ZPOOL_STATUS_INCOMPATIBLE_FEAT:
+		return PoolStatusIncompatibleFeat
+
+	case C.ZPOOL_STATUS_OK:
+		return PoolStatusOk
+
+	default:
+		// Unrecognized C status (e.g. a future libzfs release added one we
+		// don't know about yet). Don't claim the pool is healthy when we
+		// can't confirm that; PoolStatus.String() reports this as
+		// "UNKNOWN".
+		return PoolStatus(-1)
+	}
+}
+
+// StatusReport queries the pool's current PoolStatus via zpool_get_status and
+// returns it alongside the remediation action and reference URL that the
+// zpool(8) CLI would print for it, plus the pool's errata code if any.
+func (p *Pool) StatusReport() (report PoolStatusReport, err error) {
+	var cMsgID *C.char
+	var cErrata C.zpool_errata_t
+
+	status := poolStatusFromC(C.zpool_get_status(p.poolHandle, &cMsgID, &cErrata))
+
+	report.Status = status
+	report.Errata = PoolErrata(cErrata)
+	if info, ok := poolStatusTable[status]; ok {
+		report.Action = info.action
+		report.Reference = info.reference
+	}
+	return report, nil
+}
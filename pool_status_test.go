@@ -0,0 +1,65 @@
+package zfs
+
+/*
+#cgo CFLAGS: -I /usr/include/libzfs -I /usr/include/libspl -DHAVE_IOCTL_IN_SYS_IOCTL_H
+#cgo LDFLAGS: -lzfs -lzpool -lnvpair
+
+#include <stdlib.h>
+#include <libzfs.h>
+#include "zpool.h"
+*/
+import "C"
+
+import "testing"
+
+func TestPoolStatusStringCoversAllConstants(t *testing.T) {
+	for status := PoolStatusCorruptCache; status <= PoolStatusIncompatibleFeat; status++ {
+		if got := status.String(); got == "UNKNOWN" {
+			t.Errorf("PoolStatus(%d).String() = %q, want a real message", int(status), got)
+		}
+	}
+}
+
+func TestPoolStatusStringUnknown(t *testing.T) {
+	if got := PoolStatus(-1).String(); got != "UNKNOWN" {
+		t.Errorf("PoolStatus(-1).String() = %q, want UNKNOWN", got)
+	}
+}
+
+func TestPoolStatusFromC(t *testing.T) {
+	cases := []struct {
+		c    C.zpool_status_t
+		want PoolStatus
+	}{
+		{C.ZPOOL_STATUS_CORRUPT_CACHE, PoolStatusCorruptCache},
+		{C.ZPOOL_STATUS_HOSTID_MISMATCH, PoolStatusHostidMismatch},
+		// HOSTID_ACTIVE and HOSTID_REQUIRED sit between HOSTID_MISMATCH and
+		// IO_FAILURE_WAIT in the real C enum, and IO_FAILURE_MMP follows
+		// IO_FAILURE_CONTINUE; poolStatusFromC must not let them shift
+		// every status after them by raw-casting.
+		{C.ZPOOL_STATUS_HOSTID_ACTIVE, PoolStatusHostidActive},
+		{C.ZPOOL_STATUS_HOSTID_REQUIRED, PoolStatusHostidRequired},
+		{C.ZPOOL_STATUS_IO_FAILURE_WAIT, PoolStatusIoFailureWait},
+		{C.ZPOOL_STATUS_IO_FAILURE_MMP, PoolStatusIoFailureMmp},
+		{C.ZPOOL_STATUS_RESILVERING, PoolStatusResilvering},
+		{C.ZPOOL_STATUS_NON_NATIVE_ASHIFT, PoolStatusNonNativeAshift},
+		{C.ZPOOL_STATUS_OK, PoolStatusOk},
+	}
+
+	for _, c := range cases {
+		if got := poolStatusFromC(c.c); got != c.want {
+			t.Errorf("poolStatusFromC(%d) = %v, want %v", int(c.c), got, c.want)
+		}
+	}
+}
+
+func TestPoolStatusTableKeysAreValid(t *testing.T) {
+	for status, info := range poolStatusTable {
+		if status.String() == "UNKNOWN" {
+			t.Errorf("poolStatusTable has an entry for unrecognized PoolStatus %d", int(status))
+		}
+		if info.action == "" && info.reference == "" {
+			t.Errorf("poolStatusTable[%v] has neither an action nor a reference", status)
+		}
+	}
+}
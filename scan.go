@@ -0,0 +1,250 @@
+package zfs
+
+/*
+#cgo CFLAGS: -I /usr/include/libzfs -I /usr/include/libspl -DHAVE_IOCTL_IN_SYS_IOCTL_H
+#cgo LDFLAGS: -lzfs -lzpool -lnvpair
+
+#include <stdlib.h>
+#include <libzfs.h>
+#include "zpool.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+	"unsafe"
+)
+
+var (
+	errNoScanStats    = errors.New("zfs: root vdev has no scan_stats (no scrub or resilver has run)")
+	errNoRebuildStats = errors.New("zfs: no top-level vdev has rebuild_stats (no sequential resilver has run)")
+)
+
+// These keys are hoisted once, rather than C.CString()'d on every call,
+// since ScanStats/RebuildStats are exactly the calls a poller/exporter runs
+// in a loop.
+var (
+	cZpoolConfigVdevTree    = C.CString(C.ZPOOL_CONFIG_VDEV_TREE)
+	cZpoolConfigChildren    = C.CString(C.ZPOOL_CONFIG_CHILDREN)
+	cZpoolConfigScanStats   = C.CString(C.ZPOOL_CONFIG_SCAN_STATS)
+	cZpoolConfigRebuildStat = C.CString(C.ZPOOL_CONFIG_REBUILD_STATS)
+)
+
+// ScanCmd selects the action Pool.Scan() takes on a scrub or resilver.
+// Corresponds to pool_scrub_cmd_t in include/sys/fs/zfs.h.
+type ScanCmd uint64
+
+// Possible values for ScanCmd.
+const (
+	ScanCmdNormal ScanCmd = iota
+	ScanCmdPause
+)
+
+func (c ScanCmd) String() string {
+	switch c {
+	case ScanCmdNormal:
+		return "normal"
+	case ScanCmdPause:
+		return "pause"
+	default:
+		return "<UNKNOWN-VALUE>"
+	}
+}
+
+// ScanStats mirrors pool_scan_stat_t (include/sys/fs/zfs.h), the packed
+// uint64 array stored as ZPOOL_CONFIG_SCAN_STATS on the root vdev, describing
+// the progress of a scrub or resilver.
+type ScanStats struct {
+	Func  PoolScanFunc
+	State DSLScanState
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	// ToExamine and Examined are in bytes.
+	ToExamine uint64
+	Examined  uint64
+	Skipped   uint64
+	// Processed is the number of bytes written (for a resilver) or
+	// verified (for a scrub) so far.
+	Processed uint64
+	Errors    uint64
+
+	// Pass* describe the current top-of-stack pass, which restarts (and
+	// so isn't representative of overall progress) whenever a scan is
+	// resumed after being paused or interrupted.
+	PassExamined   uint64
+	PassStart      time.Time
+	PassScrubPause uint64
+}
+
+// Progress returns the fraction, in [0, 1], of the scan that has been
+// examined so far. It returns 0 if ToExamine is not yet known.
+func (s ScanStats) Progress() float64 {
+	if s.ToExamine == 0 {
+		return 0
+	}
+	if s.Examined >= s.ToExamine {
+		return 1
+	}
+	return float64(s.Examined) / float64(s.ToExamine)
+}
+
+// ETA estimates the remaining time to complete the scan, based on the
+// examination rate of the current pass. It returns 0 if the rate or
+// remaining work is unknown.
+func (s ScanStats) ETA() time.Duration {
+	elapsed := time.Since(s.PassStart)
+	if s.PassExamined == 0 || elapsed <= 0 || s.ToExamine <= s.Examined {
+		return 0
+	}
+	rate := float64(s.PassExamined) / elapsed.Seconds()
+	if rate <= 0 {
+		return 0
+	}
+	remaining := float64(s.ToExamine - s.Examined)
+	return time.Duration(remaining/rate) * time.Second
+}
+
+// BytesPerSecond returns the average examination rate of the current pass.
+func (s ScanStats) BytesPerSecond() float64 {
+	elapsed := time.Since(s.PassStart)
+	if s.PassExamined == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(s.PassExamined) / elapsed.Seconds()
+}
+
+// RebuildStats mirrors vdev_rebuild_stat_t (include/sys/fs/zfs.h), the
+// packed uint64 array stored as ZPOOL_CONFIG_REBUILD_STATS on a top-level
+// vdev undergoing sequential resilver (device replacement or "zpool attach"
+// without the traditional scan-based resilver).
+type RebuildStats struct {
+	State DSLScanState
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	ToExamine uint64
+	Examined  uint64
+	Processed uint64
+	Errors    uint64
+
+	PassDuration     time.Duration
+	PassExamined     uint64
+	PassBytesSkipped uint64
+}
+
+// scanStatsFromC casts the packed uint64 array backing
+// ZPOOL_CONFIG_SCAN_STATS to a pool_scan_stat_t and copies its pss_* members
+// out into a ScanStats.
+func scanStatsFromC(stat *C.pool_scan_stat_t) ScanStats {
+	return ScanStats{
+		Func:           PoolScanFunc(stat.pss_func),
+		State:          DSLScanState(stat.pss_state),
+		StartTime:      time.Unix(int64(stat.pss_start_time), 0),
+		EndTime:        time.Unix(int64(stat.pss_end_time), 0),
+		ToExamine:      uint64(stat.pss_to_examine),
+		Examined:       uint64(stat.pss_examined),
+		Skipped:        uint64(stat.pss_skipped),
+		Processed:      uint64(stat.pss_processed),
+		Errors:         uint64(stat.pss_errors),
+		PassExamined:   uint64(stat.pss_pass_exam),
+		PassStart:      time.Unix(int64(stat.pss_pass_start), 0),
+		PassScrubPause: uint64(stat.pss_pass_scrub_pause),
+	}
+}
+
+// rebuildStatsFromC casts the packed uint64 array backing
+// ZPOOL_CONFIG_REBUILD_STATS to a vdev_rebuild_stat_t and copies its vrs_*
+// members out into a RebuildStats.
+func rebuildStatsFromC(stat *C.vdev_rebuild_stat_t) RebuildStats {
+	return RebuildStats{
+		State:            DSLScanState(stat.vrs_state),
+		StartTime:        time.Unix(int64(stat.vrs_start_time), 0),
+		EndTime:          time.Unix(int64(stat.vrs_end_time), 0),
+		ToExamine:        uint64(stat.vrs_bytes_est),
+		Examined:         uint64(stat.vrs_bytes_scanned),
+		Processed:        uint64(stat.vrs_bytes_rebuilt),
+		Errors:           uint64(stat.vrs_errors),
+		PassDuration:     time.Duration(stat.vrs_pass_time_ms) * time.Millisecond,
+		PassExamined:     uint64(stat.vrs_pass_bytes_scanned),
+		PassBytesSkipped: uint64(stat.vrs_pass_bytes_skipped),
+	}
+}
+
+// nvlistUint64Array looks up a uint64 array member of nvl, returning the raw
+// pointer and nil on success.
+func nvlistUint64Array(nvl *C.nvlist_t, cName *C.char) (*C.uint64_t, error) {
+	var arr *C.uint64_t
+	var nelem C.uint_t
+	if C.nvlist_lookup_uint64_array(nvl, cName, &arr, &nelem) != 0 || nelem == 0 {
+		return nil, errors.New("zfs: nvlist has no such uint64 array")
+	}
+	return arr, nil
+}
+
+// ScanStats reads the pool's scan_stats, reporting the progress of any
+// in-progress or most recently completed scrub or resilver.
+//
+// scan_stats lives on the root vdev's config, not the top-level pool
+// config, and is a packed pool_scan_stat_t rather than a nested nvlist.
+func (p *Pool) ScanStats() (ScanStats, error) {
+	config := C.zpool_get_config(p.poolHandle, nil)
+	if config == nil {
+		return ScanStats{}, LastError()
+	}
+
+	var nvroot *C.nvlist_t
+	if C.nvlist_lookup_nvlist(config, cZpoolConfigVdevTree, &nvroot) != 0 {
+		return ScanStats{}, LastError()
+	}
+
+	arr, err := nvlistUint64Array(nvroot, cZpoolConfigScanStats)
+	if err != nil {
+		return ScanStats{}, errNoScanStats
+	}
+
+	return scanStatsFromC((*C.pool_scan_stat_t)(unsafe.Pointer(arr))), nil
+}
+
+// RebuildStats reads the ZPOOL_CONFIG_REBUILD_STATS of the first top-level
+// vdev undergoing (or that most recently underwent) a sequential resilver.
+func (p *Pool) RebuildStats() (RebuildStats, error) {
+	config := C.zpool_get_config(p.poolHandle, nil)
+	if config == nil {
+		return RebuildStats{}, LastError()
+	}
+
+	var nvroot *C.nvlist_t
+	if C.nvlist_lookup_nvlist(config, cZpoolConfigVdevTree, &nvroot) != 0 {
+		return RebuildStats{}, LastError()
+	}
+
+	var children **C.nvlist_t
+	var nChildren C.uint_t
+	if C.nvlist_lookup_nvlist_array(nvroot, cZpoolConfigChildren, &children, &nChildren) != 0 {
+		return RebuildStats{}, errNoRebuildStats
+	}
+
+	childSlice := unsafe.Slice(children, int(nChildren))
+	for _, child := range childSlice {
+		arr, err := nvlistUint64Array(child, cZpoolConfigRebuildStat)
+		if err != nil {
+			continue
+		}
+		return rebuildStatsFromC((*C.vdev_rebuild_stat_t)(unsafe.Pointer(arr))), nil
+	}
+
+	return RebuildStats{}, errNoRebuildStats
+}
+
+// Scan starts, pauses, resumes, or cancels a scrub or resilver, wrapping
+// zpool_scan. To cancel a running scan, pass PoolScanFuncNone.
+func (p *Pool) Scan(fn PoolScanFunc, cmd ScanCmd) error {
+	if rc := C.zpool_scan(p.poolHandle, C.pool_scan_func_t(fn), C.pool_scrub_cmd_t(cmd)); rc != 0 {
+		return LastError()
+	}
+	return nil
+}